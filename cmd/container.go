@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rapid-Vision/rv/cmd/internal/container"
+	"github.com/Rapid-Vision/rv/cmd/internal/logs"
+	"github.com/spf13/cobra"
+)
+
+var containerCmd = &cobra.Command{
+	Use:   "container",
+	Short: "Manage container images used by --runtime=docker/podman",
+}
+
+var containerPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove locally cached rv_blender_* images",
+	Run: func(cmd *cobra.Command, args []string) {
+		runtime, _ := cmd.Flags().GetString("runtime")
+
+		removed, err := container.Prune(context.Background(), runtime)
+		if err != nil {
+			logs.Err.Fatalln(err)
+		}
+
+		if len(removed) == 0 {
+			fmt.Println("No rv_blender_* images found.")
+			return
+		}
+		for _, name := range removed {
+			fmt.Println("Removed", name)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(containerCmd)
+	containerCmd.AddCommand(containerPruneCmd)
+
+	containerPruneCmd.Flags().String("runtime", "docker", "Container runtime to query: docker or podman")
+}
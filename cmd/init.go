@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Rapid-Vision/rv/cmd/internal/logs"
+	"github.com/Rapid-Vision/rv/cmd/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [dir]",
+	Short: "Scaffold a new rv project",
+	Long:  `Create a .rv/rv.yaml manifest and a starter scenes/example.py in dir (default: current directory).`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+
+		proj, err := project.Init(dir)
+		if err != nil {
+			logs.Err.Fatalln(err)
+		}
+
+		fmt.Println("Initialized rv project in", proj.Dir)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
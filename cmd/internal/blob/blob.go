@@ -0,0 +1,65 @@
+// Package blob abstracts render's output sink so a run can land on local
+// disk, S3 or GCS behind the same Storage interface.
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Storage is a pluggable sink for render output.
+type Storage interface {
+	Write(ctx context.Context, key string, r io.Reader) error
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Open dispatches on the scheme of uri — file:// (or a bare path), s3://bucket/prefix
+// or gs://bucket/prefix — and returns the matching Storage implementation.
+func Open(ctx context.Context, uri string) (Storage, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "", "file":
+		path := uri
+		if u.Scheme == "file" {
+			path = u.Path
+		}
+		return newFileStorage(path), nil
+	case "s3":
+		return newS3Storage(ctx, u.Host, strings.Trim(u.Path, "/"))
+	case "gs":
+		return newGCSStorage(ctx, u.Host, strings.Trim(u.Path, "/"))
+	default:
+		return nil, fmt.Errorf("unsupported output scheme: %q", u.Scheme)
+	}
+}
+
+// NextSequentialIndex lists prefix and returns one past the largest integer
+// found as the first path segment below it — the same numbering scheme
+// utils.GetSequentialOutputDir uses for local directories.
+func NextSequentialIndex(ctx context.Context, s Storage, prefix string) (int, error) {
+	keys, err := s.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	var maxVal int64
+	for _, key := range keys {
+		head := key
+		if idx := strings.IndexByte(key, '/'); idx >= 0 {
+			head = key[:idx]
+		}
+		if v, err := strconv.ParseInt(head, 10, 64); err == nil && v > maxVal {
+			maxVal = v
+		}
+	}
+
+	return int(maxVal) + 1, nil
+}
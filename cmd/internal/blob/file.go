@@ -0,0 +1,50 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type fileStorage struct {
+	root string
+}
+
+func newFileStorage(root string) *fileStorage {
+	return &fileStorage{root: root}
+}
+
+func (s *fileStorage) Write(ctx context.Context, key string, r io.Reader) error {
+	dst := filepath.Join(s.root, key)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (s *fileStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	dir := filepath.Join(s.root, prefix)
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
@@ -0,0 +1,59 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+type gcsStorage struct {
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+func newGCSStorage(ctx context.Context, bucket, prefix string) (*gcsStorage, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcsStorage{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *gcsStorage) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *gcsStorage) Write(ctx context.Context, key string, r io.Reader) error {
+	w := s.client.Bucket(s.bucket).Object(s.key(key)).NewWriter(ctx)
+	if _, err := io.Copy(w, r); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *gcsStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	base := s.key(prefix)
+
+	it := s.client.Bucket(s.bucket).Objects(ctx, &storage.Query{Prefix: base})
+	var names []string
+	for {
+		obj, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, strings.TrimPrefix(strings.TrimPrefix(obj.Name, base), "/"))
+	}
+	return names, nil
+}
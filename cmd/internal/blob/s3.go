@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type s3Storage struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func newS3Storage(ctx context.Context, bucket, prefix string) (*s3Storage, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Storage{client: s3.NewFromConfig(cfg), bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *s3Storage) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *s3Storage) Write(ctx context.Context, key string, r io.Reader) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+		Body:   r,
+	})
+	return err
+}
+
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	base := s.key(prefix)
+
+	var names []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(base),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(strings.TrimPrefix(aws.ToString(obj.Key), base), "/"))
+		}
+	}
+	return names, nil
+}
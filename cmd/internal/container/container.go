@@ -0,0 +1,118 @@
+// Package container lets render/preview run Blender inside a docker/podman
+// container instead of a host-resolved binary, for the --runtime flag.
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Image identifies a Blender container image, tagged by Blender version.
+type Image struct {
+	Name           string
+	BlenderVersion string
+}
+
+// RuntimeConfig selects how Blender is executed. Runtime is "local" (the
+// default, runs the host-resolved Blender directly), "docker" or "podman".
+type RuntimeConfig struct {
+	Runtime        string
+	BlenderVersion string
+}
+
+// IsContainer reports whether cfg selects a container runtime.
+func (cfg *RuntimeConfig) IsContainer() bool {
+	return cfg != nil && cfg.Runtime != "" && cfg.Runtime != "local"
+}
+
+// ImageName returns the rv_blender_<version> tag used to cache built images.
+func ImageName(blenderVersion string) string {
+	return fmt.Sprintf("rv_blender_%s", blenderVersion)
+}
+
+// Ensure makes sure a tagged image for blenderVersion exists locally under the
+// given runtime ("docker" or "podman"), building it if it doesn't.
+func Ensure(ctx context.Context, runtime string, image Image) error {
+	exists, err := imageExists(ctx, runtime, image.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	return buildImage(ctx, runtime, image)
+}
+
+func imageExists(ctx context.Context, runtime string, name string) (bool, error) {
+	cmd := exec.CommandContext(ctx, runtime, "images", "--format", "{{.Repository}}:{{.Tag}}")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return false, err
+	}
+
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line == name || strings.HasPrefix(line, name+":") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func buildImage(ctx context.Context, runtime string, image Image) error {
+	dockerfile := fmt.Sprintf("FROM blender:%s\n", image.BlenderVersion)
+
+	cmd := exec.CommandContext(ctx, runtime, "build", "-t", image.Name, "-")
+	cmd.Stdin = strings.NewReader(dockerfile)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// WrapArgv prepends a `docker/podman run` invocation to argv (a Blender
+// command line) so it executes inside image, with libPath, scriptDir,
+// outputDir and cwd bind-mounted at identical in-container paths, and cwd
+// set as the container's working directory.
+func WrapArgv(runtime string, image Image, libPath, scriptDir, outputDir, cwd string, argv []string) []string {
+	wrapped := []string{runtime, "run", "--rm", "-i", "--network", "host"}
+
+	seen := make(map[string]bool)
+	for _, dir := range []string{libPath, scriptDir, outputDir, cwd} {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		wrapped = append(wrapped, "-v", fmt.Sprintf("%s:%s", dir, dir))
+	}
+
+	wrapped = append(wrapped, "-w", cwd, image.Name)
+	return append(wrapped, argv...)
+}
+
+// Prune removes every locally cached rv_blender_* image and returns the names
+// it removed.
+func Prune(ctx context.Context, runtime string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, runtime, "images", "--format", "{{.Repository}}:{{.Tag}}")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" || !strings.HasPrefix(line, "rv_blender_") {
+			continue
+		}
+		if err := exec.CommandContext(ctx, runtime, "rmi", line).Run(); err != nil {
+			return removed, fmt.Errorf("failed to remove image %s: %w", line, err)
+		}
+		removed = append(removed, line)
+	}
+	return removed, nil
+}
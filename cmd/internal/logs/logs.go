@@ -1,7 +1,9 @@
 package logs
 
 import (
+	"io"
 	"log"
+	"strings"
 
 	"github.com/fatih/color"
 )
@@ -11,3 +13,22 @@ var (
 	Warn = log.New(color.Output, color.HiYellowString("[WARN] "), log.Lmsgprefix)
 	Err  = log.New(color.Output, color.HiRedString("[ERROR] "), log.Lmsgprefix)
 )
+
+// prefixWriter writes each line it receives to Info, prepending a fixed prefix.
+// Useful for labelling interleaved output from several subprocesses.
+type prefixWriter struct {
+	prefix string
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		Info.Printf("%s%s", w.prefix, line)
+	}
+	return len(p), nil
+}
+
+// NewPrefixWriter returns an io.Writer suitable for cmd.Stdout/cmd.Stderr that
+// labels every line written to it with prefix before logging it through Info.
+func NewPrefixWriter(prefix string) io.Writer {
+	return &prefixWriter{prefix: prefix}
+}
@@ -0,0 +1,121 @@
+package pack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Archive packages srcDir into a single archive next to it (srcDir.zip,
+// srcDir.tar or srcDir.tar.gz) according to kind, and returns the archive's
+// path ("" for kind "none").
+func Archive(kind string, srcDir string) (string, error) {
+	switch kind {
+	case "", "none":
+		return "", nil
+	case "zip":
+		dest := srcDir + ".zip"
+		return dest, zipDir(srcDir, dest)
+	case "tar":
+		dest := srcDir + ".tar"
+		return dest, tarDir(srcDir, dest, false)
+	case "tar.gz":
+		dest := srcDir + ".tar.gz"
+		return dest, tarDir(srcDir, dest, true)
+	default:
+		return "", fmt.Errorf("unknown --pack kind: %q", kind)
+	}
+}
+
+func zipDir(srcDir, destPath string) error {
+	out, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	defer zw.Close()
+
+	return filepath.WalkDir(srcDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+
+		w, err := zw.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		return err
+	})
+}
+
+func tarDir(srcDir, destPath string, gz bool) error {
+	out, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var w io.Writer = out
+	if gz {
+		gzw := gzip.NewWriter(out)
+		defer gzw.Close()
+		w = gzw
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	return filepath.WalkDir(srcDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
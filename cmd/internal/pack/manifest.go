@@ -0,0 +1,162 @@
+// Package pack turns a finished render output directory into a
+// self-describing, distributable artifact: a manifest.json plus an optional
+// zip/tar/tar.gz archive.
+package pack
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileEntry is one file in a Manifest's sorted file list.
+type FileEntry struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes a completed render run well enough for downstream
+// dataset-versioning tools to tell two runs apart (or confirm they match).
+// It holds only content derived from the run's inputs and outputs, so two
+// identical runs with the same --seed produce byte-identical manifests; see
+// RunInfo for the wall-clock timing that would otherwise break that.
+type Manifest struct {
+	RVVersion      string      `json:"rv_version"`
+	BlenderVersion string      `json:"blender_version"`
+	ScriptPath     string      `json:"script_path"`
+	ScriptSHA256   string      `json:"script_sha256"`
+	GitCommit      string      `json:"git_commit,omitempty"`
+	ImageCount     int         `json:"image_count"`
+	Argv           [][]string  `json:"argv"`
+	Files          []FileEntry `json:"files"`
+}
+
+// RunInfo records the wall-clock timing of a render run. It's written
+// alongside manifest.json as run.json instead of into the manifest itself,
+// since embedding it there would make every run's manifest differ from
+// every other, defeating the point of diffing them.
+type RunInfo struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// BuildManifest hashes scriptPath and every file in dir, and records the git
+// commit scriptPath is checked out at, if any. argv is the exact command
+// line each worker was started with, in worker order. The file list is
+// sorted by path, so two identical runs with the same --seed produce
+// byte-identical manifests.
+func BuildManifest(dir, scriptPath, rvVersion, blenderVersion string, argv [][]string) (Manifest, error) {
+	scriptHash, err := sha256File(scriptPath)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	files, err := hashDir(dir)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return Manifest{
+		RVVersion:      rvVersion,
+		BlenderVersion: blenderVersion,
+		ScriptPath:     scriptPath,
+		ScriptSHA256:   scriptHash,
+		GitCommit:      gitCommit(filepath.Dir(scriptPath)),
+		ImageCount:     countImages(files),
+		Argv:           argv,
+		Files:          files,
+	}, nil
+}
+
+// countImages counts distinct samples in files, grouping by basename stem the
+// same way render.mergeWorkerOutputs does, so an image and its sidecar files
+// (e.g. a mask or metadata sharing the image's stem) count once instead of
+// once per file.
+func countImages(files []FileEntry) int {
+	stems := map[string]bool{}
+	for _, f := range files {
+		stem := strings.TrimSuffix(f.Path, filepath.Ext(f.Path))
+		stems[stem] = true
+	}
+	return len(stems)
+}
+
+// Write marshals m as indented JSON into dir/manifest.json.
+func Write(dir string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644)
+}
+
+// WriteRunInfo marshals info as indented JSON into dir/run.json.
+func WriteRunInfo(dir string, info RunInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "run.json"), data, 0644)
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashDir(dir string) ([]FileEntry, error) {
+	var files []FileEntry
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		hash, err := sha256File(p)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, FileEntry{Path: filepath.ToSlash(rel), SHA256: hash})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func gitCommit(dir string) string {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "HEAD")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out.String())
+}
@@ -0,0 +1,62 @@
+package preview
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var (
+	importRe     = regexp.MustCompile(`^\s*import\s+([\w.]+)`)
+	fromImportRe = regexp.MustCompile(`^\s*from\s+([\w.]+)\s+import\s`)
+)
+
+// siblingImports does a best-effort static scan of scriptPath for
+// `import x` / `from x import y` statements and returns the absolute paths
+// of any `x.py` found next to the script.
+func siblingImports(scriptPath string) ([]string, error) {
+	f, err := os.Open(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scriptDir := filepath.Dir(scriptPath)
+	seen := map[string]bool{}
+	var resolved []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		module := ""
+		if m := importRe.FindStringSubmatch(line); m != nil {
+			module = m[1]
+		} else if m := fromImportRe.FindStringSubmatch(line); m != nil {
+			module = m[1]
+		}
+		if module == "" {
+			continue
+		}
+
+		top := module
+		for i, r := range module {
+			if r == '.' {
+				top = module[:i]
+				break
+			}
+		}
+
+		sibling := filepath.Join(scriptDir, top+".py")
+		if seen[sibling] {
+			continue
+		}
+		if _, err := os.Stat(sibling); err == nil {
+			seen[sibling] = true
+			resolved = append(resolved, sibling)
+		}
+	}
+
+	return resolved, scanner.Err()
+}
@@ -9,48 +9,77 @@ import (
 	"path/filepath"
 	"syscall"
 
+	"github.com/Rapid-Vision/rv/cmd/internal/container"
 	"github.com/Rapid-Vision/rv/cmd/internal/utils"
 	"github.com/Rapid-Vision/rv/cmd/internal/watcher"
 )
 
-func Preview(scriptPath string) {
-	blenderPath, err := utils.GetBlenderPath()
+func Preview(scriptPath string, runtime *container.RuntimeConfig, extraWatch []string) {
+	var blenderPath string
+	var err error
+	if !runtime.IsContainer() {
+		blenderPath, err = utils.GetBlenderPath()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	libPath, err := utils.GetLibPath()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	libPath, err := utils.GetLibPath()
+	port, err := utils.GetPort()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	port, err := utils.GetPort()
+	watchPort, err := utils.GetPort()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	// Start Blender
-	cmd := exec.Command(
-		blenderPath,
+	var image container.Image
+	if runtime.IsContainer() {
+		image = container.Image{Name: container.ImageName(runtime.BlenderVersion), BlenderVersion: runtime.BlenderVersion}
+		if err := container.Ensure(context.Background(), runtime.Runtime, image); err != nil {
+			fmt.Fprintln(os.Stderr, "Can't prepare container image:", err)
+			os.Exit(1)
+		}
+	}
+
+	argv := []string{
 		filepath.Join(libPath, "template.blend"),
 		"--factory-startup",
 		"--python", filepath.Join(libPath, "preview.py"),
 		"--",
 		"--port", fmt.Sprintf("%d", port),
+		"--watch-port", fmt.Sprintf("%d", watchPort),
 		"--script", scriptPath,
 		"--libpath", libPath,
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	}
 
-	if err = cmd.Start(); err != nil {
-		fmt.Fprintln(os.Stderr, "failed to start blender:", err)
-		os.Exit(1)
+	// Start Blender — --network host keeps the container reachable at
+	// 127.0.0.1:<port> just like the local runtime.
+	var cmd *exec.Cmd
+	if runtime.IsContainer() {
+		cwd, err := utils.GetAbsCwdPath()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		scriptDir := filepath.Dir(scriptPath)
+		wrapped := container.WrapArgv(runtime.Runtime, image, libPath, scriptDir, scriptDir, cwd, append([]string{"blender"}, argv...))
+		cmd = exec.Command(wrapped[0], wrapped[1:]...)
+	} else {
+		cmd = exec.Command(blenderPath, argv...)
 	}
-	fmt.Printf("Blender started (PID %d) on port %d\n", cmd.Process.Pid, port)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
 
 	// Context for shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -58,9 +87,51 @@ func Preview(scriptPath string) {
 
 	client := newPreviewClient(port)
 
+	w, err := watcher.New(0)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start watcher:", err)
+		os.Exit(1)
+	}
+	defer w.Close()
+
+	if err := w.Add(scriptPath); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to watch", scriptPath, ":", err)
+		os.Exit(1)
+	}
+
+	if imports, err := siblingImports(scriptPath); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to scan imports:", err)
+	} else {
+		for _, sibling := range imports {
+			if err := w.Add(sibling); err != nil {
+				fmt.Fprintln(os.Stderr, "failed to watch", sibling, ":", err)
+			}
+		}
+	}
+
+	for _, pattern := range extraWatch {
+		if err := w.Add(pattern); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to watch", pattern, ":", err)
+		}
+	}
+
+	// Lets the running preview.py register extra paths it opens at runtime.
+	// Must be listening before Blender starts, since preview.py can begin
+	// registering paths (e.g. via bpy.data.libraries.load) as soon as it's up.
+	if err := startWatchServer(watchPort, w); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start watch server:", err)
+		os.Exit(1)
+	}
+
+	if err = cmd.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start blender:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Blender started (PID %d) on port %d\n", cmd.Process.Pid, port)
+
 	// Watcher goroutine
 	go func() {
-		if err := watcher.WatchFile(ctx, scriptPath, client.requestRerun); err != nil {
+		if err := w.Run(ctx, client.requestRerun); err != nil {
 			fmt.Fprintln(os.Stderr, "watch error:", err)
 		}
 	}()
@@ -0,0 +1,50 @@
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Rapid-Vision/rv/cmd/internal/logs"
+	"github.com/Rapid-Vision/rv/cmd/internal/watcher"
+)
+
+// watchRegisterRequest is the body preview.py POSTs to register a path it
+// opened at runtime (e.g. via bpy.data.libraries.load), so watch coverage
+// grows as the scene is built.
+type watchRegisterRequest struct {
+	Path string `json:"path"`
+}
+
+// startWatchServer listens on port and adds any path POSTed to /watch to w.
+// It runs for the lifetime of the process; callers don't need to shut it
+// down explicitly since Preview exits the whole process together with it.
+func startWatchServer(port int, w *watcher.Watcher) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/watch", func(rw http.ResponseWriter, r *http.Request) {
+		var req watchRegisterRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := w.Add(req.Path); err != nil {
+			logs.Warn.Println("failed to watch", req.Path, ":", err)
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		_ = http.Serve(ln, mux)
+	}()
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package project reads and writes the .rv/rv.yaml manifest that `rv init`
+// scaffolds, and lets render/preview pick up their defaults from it.
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrNotInit     = errors.New("project: not inside an rv project (no .rv/rv.yaml found)")
+	ErrAlreadyInit = errors.New("project: already initialized (.rv/rv.yaml exists)")
+)
+
+const (
+	dirName      = ".rv"
+	manifestName = "rv.yaml"
+)
+
+// Scene is one entry of the manifest's `scenes:` list, looked up by name via
+// `rv render --scene <name>`.
+type Scene struct {
+	Name   string `yaml:"name"`
+	Script string `yaml:"script"`
+	Number int    `yaml:"number"`
+	Output string `yaml:"output"`
+}
+
+// Manifest is the schema of rv.yaml.
+type Manifest struct {
+	BlenderPath   string  `yaml:"blender_path"`
+	RVLibPath     string  `yaml:"rvlib_path"`
+	DefaultOutput string  `yaml:"default_output"`
+	Procs         int     `yaml:"procs"`
+	Runtime       string  `yaml:"runtime"`
+	Scenes        []Scene `yaml:"scenes"`
+}
+
+// Project is a loaded rv.yaml plus the directory it was found in.
+type Project struct {
+	Dir      string
+	Manifest Manifest
+}
+
+// Open walks up from dir looking for .rv/rv.yaml and returns the loaded
+// project, or ErrNotInit if it reaches the filesystem root without finding
+// one.
+func Open(dir string) (*Project, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		manifestPath := filepath.Join(abs, dirName, manifestName)
+		if _, err := os.Stat(manifestPath); err == nil {
+			return load(abs, manifestPath)
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, ErrNotInit
+		}
+		abs = parent
+	}
+}
+
+func load(dir, manifestPath string) (*Project, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return &Project{Dir: dir, Manifest: m}, nil
+}
+
+// Scene looks up a scene by name.
+func (p *Project) Scene(name string) (Scene, bool) {
+	for _, s := range p.Manifest.Scenes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Scene{}, false
+}
+
+// Init scaffolds <dir>/.rv/rv.yaml with a default manifest and a starter
+// scenes/example.py. It fails with ErrAlreadyInit if a manifest already
+// exists.
+func Init(dir string) (*Project, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rvDir := filepath.Join(abs, dirName)
+	manifestPath := filepath.Join(rvDir, manifestName)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil, ErrAlreadyInit
+	}
+
+	if err := os.MkdirAll(rvDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	m := defaultManifest()
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		return nil, err
+	}
+
+	scenesDir := filepath.Join(abs, "scenes")
+	if err := os.MkdirAll(scenesDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	examplePath := filepath.Join(scenesDir, "example.py")
+	if _, err := os.Stat(examplePath); os.IsNotExist(err) {
+		if err := os.WriteFile(examplePath, []byte(exampleScript), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Project{Dir: abs, Manifest: m}, nil
+}
+
+func defaultManifest() Manifest {
+	return Manifest{
+		DefaultOutput: "./out",
+		Procs:         1,
+		Runtime:       "local",
+		Scenes: []Scene{
+			{Name: "example", Script: "scenes/example.py", Number: 1},
+		},
+	}
+}
+
+const exampleScript = `import bpy
+
+
+def generate():
+    pass
+`
@@ -3,20 +3,40 @@ package render
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
+	"github.com/Rapid-Vision/rv/cmd/internal/blob"
+	"github.com/Rapid-Vision/rv/cmd/internal/container"
+	"github.com/Rapid-Vision/rv/cmd/internal/logs"
+	"github.com/Rapid-Vision/rv/cmd/internal/pack"
 	"github.com/Rapid-Vision/rv/cmd/internal/utils"
 )
 
-func Render(scriptPath string, imgNum int, procs int, outputDir string) {
-	blenderPath, err := utils.GetBlenderPath()
-	if err != nil {
-		log.Fatalln(err)
+// rvVersion is a placeholder until rv grows a real release process that
+// stamps it at build time.
+const rvVersion = "dev"
+
+func Render(scriptPath string, imgNum int, procs int, outputURI string, seed int, runtime *container.RuntimeConfig, keepLocal bool, storageParallelism int, packKind string) {
+	startedAt := time.Now()
+
+	var blenderPath string
+	var err error
+	if !runtime.IsContainer() {
+		blenderPath, err = utils.GetBlenderPath()
+		if err != nil {
+			log.Fatalln(err)
+		}
 	}
 
 	libPath, err := utils.GetLibPath()
@@ -26,52 +46,354 @@ func Render(scriptPath string, imgNum int, procs int, outputDir string) {
 
 	fmt.Println("librv path: ", libPath)
 
-	seqOutDir, err := utils.GetSequentialOutputDir(outputDir)
+	sink, runID, seqOutDir, err := resolveOutput(context.Background(), outputURI)
 	if err != nil {
-		log.Fatalln("Can't create new output directory: ", err)
-	}
-
-	// Start Blender
-	cmd := exec.Command(
-		blenderPath,
-		filepath.Join(libPath, "template.blend"),
-		"--factory-startup",
-		"--background",
-		"--python", filepath.Join(libPath, "render.py"),
-		"--",
-		"--script", scriptPath,
-		"--libpath", libPath,
-		"--number", fmt.Sprintf("%d", imgNum),
-		"--output", seqOutDir,
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err = cmd.Start(); err != nil {
-		log.Fatalln("failed to start blender:", err)
-	}
-	fmt.Printf("Blender started (PID %d)\n", cmd.Process.Pid)
-
-	// Context for shutdown
-	_, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle Ctrl-C
+		log.Fatalln("Can't resolve output:", err)
+	}
+
+	var image container.Image
+	if runtime.IsContainer() {
+		image = container.Image{Name: container.ImageName(runtime.BlenderVersion), BlenderVersion: runtime.BlenderVersion}
+		if err := container.Ensure(context.Background(), runtime.Runtime, image); err != nil {
+			log.Fatalln("Can't prepare container image: ", err)
+		}
+	}
+
+	cwd, err := utils.GetAbsCwdPath()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	scriptDir := filepath.Dir(scriptPath)
+
+	// Start one Blender worker per proc, each rendering its own share of imgNum
+	// into an isolated subdirectory.
+	var cmds []*exec.Cmd
+	var workerDirs []string
+	var workerArgvs [][]string
+
+	for i := 0; i < procs; i++ {
+		n := utils.SplitTaskBetweenProcs(imgNum, procs, i)
+		if n == 0 {
+			continue
+		}
+
+		workerDir := filepath.Join(seqOutDir, fmt.Sprintf("worker-%d", i))
+		if err := os.MkdirAll(workerDir, os.ModePerm); err != nil {
+			log.Fatalln("Can't create worker output directory: ", err)
+		}
+
+		argv := []string{
+			filepath.Join(libPath, "template.blend"),
+			"--factory-startup",
+			"--background",
+			"--python", filepath.Join(libPath, "render.py"),
+			"--",
+			"--script", scriptPath,
+			"--libpath", libPath,
+			"--number", fmt.Sprintf("%d", n),
+			"--output", workerDir,
+			"--seed", fmt.Sprintf("%d", seed+i),
+		}
+
+		var cmd *exec.Cmd
+		var fullArgv []string
+		if runtime.IsContainer() {
+			wrapped := container.WrapArgv(runtime.Runtime, image, libPath, scriptDir, workerDir, cwd, append([]string{"blender"}, argv...))
+			cmd = exec.Command(wrapped[0], wrapped[1:]...)
+			fullArgv = wrapped
+		} else {
+			cmd = exec.Command(blenderPath, argv...)
+			fullArgv = append([]string{blenderPath}, argv...)
+		}
+		cmd.Stdout = logs.NewPrefixWriter(fmt.Sprintf("[worker %d] ", i))
+		cmd.Stderr = logs.NewPrefixWriter(fmt.Sprintf("[worker %d] ", i))
+
+		if err := cmd.Start(); err != nil {
+			for _, started := range cmds {
+				_ = started.Process.Signal(syscall.SIGTERM)
+			}
+			log.Fatalln("failed to start blender:", err)
+		}
+		fmt.Printf("Blender worker %d started (PID %d)\n", i, cmd.Process.Pid)
+
+		cmds = append(cmds, cmd)
+		workerDirs = append(workerDirs, workerDir)
+		workerArgvs = append(workerArgvs, fullArgv)
+	}
+
+	// Handle Ctrl-C by broadcasting SIGTERM to every worker.
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
-	// Wait for either Blender exit or signal
-	select {
-	case <-sigCh:
-		fmt.Println("Interrupt received — terminating Blender…")
-		_ = cmd.Process.Signal(syscall.SIGTERM)
-	case err = <-utils.WaitCmd(cmd):
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Blender exited with error:", err)
+	doneCh := utils.WaitCmdBuff(cmds)
+	var workerErrs []error
+	interrupted := false
+
+	for remaining := len(cmds); remaining > 0; {
+		select {
+		case <-sigCh:
+			fmt.Println("Interrupt received — terminating all workers…")
+			interrupted = true
+			for _, cmd := range cmds {
+				_ = cmd.Process.Signal(syscall.SIGTERM)
+			}
+			sigCh = nil
+		case err := <-doneCh:
+			if err != nil {
+				workerErrs = append(workerErrs, err)
+			}
+			remaining--
+		}
+	}
+
+	for _, err := range workerErrs {
+		fmt.Fprintln(os.Stderr, "worker exited with error:", err)
+	}
+	if len(workerErrs) == 0 {
+		fmt.Println("All Blender workers exited.")
+	}
+
+	if interrupted {
+		fmt.Printf("Render interrupted; leaving unmerged per-worker output in %s.\n", seqOutDir)
+		return
+	}
+
+	if err := mergeWorkerOutputs(seqOutDir, workerDirs); err != nil {
+		log.Fatalln("Can't merge worker output directories: ", err)
+	}
+
+	manifest, err := pack.BuildManifest(seqOutDir, scriptPath, rvVersion, blenderVersionString(blenderPath, runtime), normalizeArgvs(workerArgvs, seqOutDir))
+	if err != nil {
+		log.Fatalln("Can't build manifest: ", err)
+	}
+	if err := pack.Write(seqOutDir, manifest); err != nil {
+		log.Fatalln("Can't write manifest: ", err)
+	}
+	if err := pack.WriteRunInfo(seqOutDir, pack.RunInfo{StartedAt: startedAt, FinishedAt: time.Now()}); err != nil {
+		log.Fatalln("Can't write run info: ", err)
+	}
+
+	archivePath, err := pack.Archive(packKind, seqOutDir)
+	if err != nil {
+		log.Fatalln("Can't pack output directory: ", err)
+	}
+	if archivePath != "" {
+		fmt.Println("Packed output into", archivePath)
+	}
+
+	if sink != nil {
+		ctx := context.Background()
+		if archivePath != "" {
+			fmt.Println("Uploading", archivePath, "to", outputURI)
+			if err := uploadFile(ctx, sink, archivePath, path.Join(runID, filepath.Base(archivePath))); err != nil {
+				log.Fatalln("Can't upload archive: ", err)
+			}
 		} else {
-			fmt.Println("Blender exited.")
+			fmt.Println("Uploading", seqOutDir, "to", outputURI)
+			if err := uploadDir(ctx, sink, seqOutDir, runID, storageParallelism); err != nil {
+				log.Fatalln("Can't upload output directory: ", err)
+			}
+		}
+		if !keepLocal {
+			if err := os.RemoveAll(seqOutDir); err != nil {
+				log.Fatalln("Can't clean up local output directory: ", err)
+			}
+			if archivePath != "" {
+				if err := os.Remove(archivePath); err != nil {
+					log.Fatalln("Can't clean up local archive: ", err)
+				}
+			}
+		}
+	}
+}
+
+// blenderVersionString reports the Blender version used for this run, for
+// the manifest: the container runtime's configured tag, or the local
+// binary's own `--version` output.
+func blenderVersionString(blenderPath string, runtime *container.RuntimeConfig) string {
+	if runtime.IsContainer() {
+		return runtime.BlenderVersion
+	}
+
+	out, err := exec.Command(blenderPath, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+}
+
+// resolveOutput turns --output into a local directory to render into plus,
+// for URIs backed by a blob.Storage (file://, s3://, gs://), the sink to
+// upload it to once rendering is done. For a bare local path, it's business
+// as usual: seqOutDir is the next sequential subdirectory of outputURI and
+// sink is nil.
+func resolveOutput(ctx context.Context, outputURI string) (sink blob.Storage, runID string, seqOutDir string, err error) {
+	if !hasStorageScheme(outputURI) {
+		seqOutDir, err = utils.GetSequentialOutputDir(outputURI)
+		return nil, "", seqOutDir, err
+	}
+
+	sink, err = blob.Open(ctx, outputURI)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	idx, err := blob.NextSequentialIndex(ctx, sink, "")
+	if err != nil {
+		return nil, "", "", err
+	}
+	runID = fmt.Sprint(idx)
+
+	seqOutDir, err = os.MkdirTemp("", "rv-render-*")
+	return sink, runID, seqOutDir, err
+}
+
+// hasStorageScheme reports whether uri names one of the blob.Storage schemes
+// (file://, s3://, gs://) rather than a bare local path.
+func hasStorageScheme(uri string) bool {
+	for _, scheme := range []string{"file://", "s3://", "gs://"} {
+		if len(uri) >= len(scheme) && uri[:len(scheme)] == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeArgvs replaces every occurrence of seqOutDir in argvs with a fixed
+// placeholder before it's recorded in the manifest. seqOutDir is either the
+// next auto-incrementing local run directory or a randomly-named temp dir for
+// a remote --output, so baking it in verbatim would make two identical runs
+// with the same --seed produce different manifests.
+func normalizeArgvs(argvs [][]string, seqOutDir string) [][]string {
+	normalized := make([][]string, len(argvs))
+	for i, argv := range argvs {
+		row := make([]string, len(argv))
+		for j, arg := range argv {
+			row[j] = strings.ReplaceAll(arg, seqOutDir, "<out>")
+		}
+		normalized[i] = row
+	}
+	return normalized
+}
+
+// uploadDir walks dir and writes every file it contains to sink under
+// runID, bounding concurrency at parallelism uploads at a time.
+func uploadDir(ctx context.Context, sink blob.Storage, dir string, runID string, parallelism int) error {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	sem := make(chan struct{}, parallelism)
+	errCh := make(chan error, len(files))
+	var wg sync.WaitGroup
+
+	for _, f := range files {
+		rel, err := filepath.Rel(dir, f)
+		if err != nil {
+			return err
+		}
+		key := path.Join(runID, filepath.ToSlash(rel))
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(f, key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			file, err := os.Open(f)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			defer file.Close()
+
+			errCh <- sink.Write(ctx, key, file)
+		}(f, key)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uploadFile writes a single local file to sink under key.
+func uploadFile(ctx context.Context, sink blob.Storage, localPath, key string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return sink.Write(ctx, key, f)
+}
+
+// mergeWorkerOutputs flattens the per-worker output directories into seqOutDir,
+// renumbering files in worker order so the result looks like one contiguous run.
+// Files sharing a basename (e.g. "0003.png" and "0003.json" for an image and
+// its mask/metadata sidecar) are treated as one sample and renumbered
+// together, so the pairing between an image and its sidecars survives the
+// merge.
+func mergeWorkerOutputs(seqOutDir string, workerDirs []string) error {
+	globalIdx := 0
+
+	for _, workerDir := range workerDirs {
+		entries, err := os.ReadDir(workerDir)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			if !e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+
+		groups := map[string][]string{}
+		var stems []string
+		for _, name := range names {
+			stem := strings.TrimSuffix(name, filepath.Ext(name))
+			if _, ok := groups[stem]; !ok {
+				stems = append(stems, stem)
+			}
+			groups[stem] = append(groups[stem], name)
+		}
+		sort.Strings(stems)
+
+		for _, stem := range stems {
+			for _, name := range groups[stem] {
+				ext := filepath.Ext(name)
+				dst := filepath.Join(seqOutDir, fmt.Sprintf("%06d%s", globalIdx, ext))
+				if err := os.Rename(filepath.Join(workerDir, name), dst); err != nil {
+					return err
+				}
+			}
+			globalIdx++
+		}
+
+		if err := os.Remove(workerDir); err != nil {
+			return err
 		}
 	}
 
-	cancel()
+	return nil
 }
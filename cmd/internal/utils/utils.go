@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"strconv"
 
+	"github.com/Rapid-Vision/rv/cmd/internal/project"
 	"github.com/Rapid-Vision/rv/rvlib"
 )
 
@@ -26,19 +27,26 @@ func GetPort() (int, error) {
 
 // try several locations to find a Blender executable.
 func GetBlenderPath() (string, error) {
-	// 1. BLENDER_PATH env var
+	// 1. blender_path in the enclosing project's rv.yaml, if any
+	if proj, err := project.Open("."); err == nil && proj.Manifest.BlenderPath != "" {
+		if _, err := os.Stat(proj.Manifest.BlenderPath); err == nil {
+			return proj.Manifest.BlenderPath, nil
+		}
+	}
+
+	// 2. BLENDER_PATH env var
 	if p := os.Getenv("BLENDER_PATH"); p != "" {
 		if _, err := os.Stat(p); err == nil {
 			return p, nil
 		}
 	}
 
-	// 2. LookPath
+	// 3. LookPath
 	if p, err := exec.LookPath("blender"); err == nil {
 		return p, nil
 	}
 
-	// 3. Platform-specific fallbacks
+	// 4. Platform-specific fallbacks
 	switch runtime.GOOS {
 	case "darwin":
 		p := "/Applications/Blender.app/Contents/MacOS/Blender"
@@ -57,12 +65,17 @@ func GetBlenderPath() (string, error) {
 
 // try several locations to find a rvlib
 func GetLibPath() (string, error) {
-	// 1. RVLIB_PATH env var
+	// 1. rvlib_path in the enclosing project's rv.yaml, if any
+	if proj, err := project.Open("."); err == nil && proj.Manifest.RVLibPath != "" {
+		return proj.Manifest.RVLibPath, nil
+	}
+
+	// 2. RVLIB_PATH env var
 	if p := os.Getenv("RVLIB_PATH"); p != "" {
 		return p, nil
 	}
 
-	// 2. Check if local rvlib directory exists
+	// 3. Check if local rvlib directory exists
 	localPath := "./rvlib/rvlib/"
 	if _, err := os.Stat(localPath); err == nil {
 		absPath, err := filepath.Abs(localPath)
@@ -71,7 +84,7 @@ func GetLibPath() (string, error) {
 		}
 	}
 
-	// 3. Unpack embedded rvlib into .cache/rvlib
+	// 4. Unpack embedded rvlib into .cache/rvlib
 
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
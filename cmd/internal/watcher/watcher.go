@@ -1,38 +1,280 @@
+// Package watcher watches a growing set of files, directories and glob
+// patterns for changes and delivers a single coalesced "changed" event per
+// debounce window.
 package watcher
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
-func WatchFile(ctx context.Context, path string, callback func()) error {
+const defaultDebounce = 200 * time.Millisecond
+
+// Watcher watches files (exact paths), directories (recursively) and glob
+// patterns such as "assets/**/*.blend", deduplicating fsnotify subscriptions
+// per parent directory. Editors that write-then-rename a file fire several
+// raw fsnotify events per save; Watcher coalesces these into a single
+// callback per debounce window.
+type Watcher struct {
+	debounce time.Duration
+	fsw      *fsnotify.Watcher
+
+	mu           sync.Mutex
+	watchedFiles map[string]bool
+	watchedDirs  map[string]bool // recursive roots
+	subscribed   map[string]bool // directories already registered with fsw
+	globs        []*regexp.Regexp
+}
+
+// New creates a Watcher with the given debounce window; a zero debounce
+// defaults to 200ms.
+func New(debounce time.Duration) (*Watcher, error) {
+	if debounce <= 0 {
+		debounce = defaultDebounce
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		debounce:     debounce,
+		fsw:          fsw,
+		watchedFiles: map[string]bool{},
+		watchedDirs:  map[string]bool{},
+		subscribed:   map[string]bool{},
+	}, nil
+}
+
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// Add registers a file, a directory (watched recursively) or a glob pattern
+// to the watch set. Safe to call concurrently, including while Run is
+// executing — this is how preview.py registers paths it opens at runtime.
+func (w *Watcher) Add(path string) error {
+	if strings.ContainsAny(path, "*?") {
+		return w.addGlob(path)
+	}
+
 	abs, err := filepath.Abs(path)
 	if err != nil {
 		return err
 	}
-	dir := filepath.Dir(abs)
-	w, err := fsnotify.NewWatcher()
+
+	info, err := os.Stat(abs)
 	if err != nil {
 		return err
 	}
-	defer w.Close()
 
-	if err = w.Add(dir); err != nil {
+	if info.IsDir() {
+		return w.addDir(abs)
+	}
+
+	w.mu.Lock()
+	w.watchedFiles[abs] = true
+	w.mu.Unlock()
+	return w.subscribeDir(filepath.Dir(abs))
+}
+
+func (w *Watcher) addDir(root string) error {
+	w.mu.Lock()
+	w.watchedDirs[root] = true
+	w.mu.Unlock()
+
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return w.subscribeDir(p)
+		}
+		return nil
+	})
+}
+
+// addGlob registers pattern and eagerly subscribes to every directory under
+// its static (non-wildcard) prefix, so files created later still match.
+func (w *Watcher) addGlob(pattern string) error {
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.globs = append(w.globs, re)
+	w.mu.Unlock()
+
+	root := staticGlobPrefix(pattern)
+	if root == "" {
+		root = "."
+	}
+
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // prefix may not exist yet; it can be created later
+		}
+		if d.IsDir() {
+			return w.subscribeDir(p)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) subscribeDir(dir string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.subscribed[dir] {
+		return nil
+	}
+	if err := w.fsw.Add(dir); err != nil {
 		return err
 	}
+	w.subscribed[dir] = true
+	return nil
+}
+
+// subscribeNewDir subscribes name and, recursively, everything already inside
+// it, if it's a directory. It's a no-op for anything else, including paths
+// that no longer exist by the time we stat them.
+func (w *Watcher) subscribeNewDir(name string) {
+	info, err := os.Stat(name)
+	if err != nil || !info.IsDir() {
+		return
+	}
+
+	_ = filepath.WalkDir(name, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			return w.subscribeDir(p)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) matches(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.watchedFiles[name] {
+		return true
+	}
+	for dir := range w.watchedDirs {
+		if name == dir || strings.HasPrefix(name, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	for _, re := range w.globs {
+		if re.MatchString(filepath.ToSlash(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run blocks, invoking onChange at most once per debounce window whenever a
+// watched path is created, written, or renamed into place.
+func (w *Watcher) Run(ctx context.Context, onChange func()) error {
+	var timer *time.Timer
+	var timerCh <-chan time.Time
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case ev := <-w.Events:
-			if ev.Op&fsnotify.Write == fsnotify.Write && ev.Name == abs {
-				callback()
+		case ev := <-w.fsw.Events:
+			if ev.Op&fsnotify.Create != 0 {
+				// A directory created under an already-subscribed parent may
+				// fall inside a watched root or glob prefix; subscribe it —
+				// and anything already inside it — so changes under it aren't
+				// silently missed. This also subscribes new directories next
+				// to individually watched files, whose events matches() then
+				// filters out.
+				w.subscribeNewDir(ev.Name)
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if !w.matches(ev.Name) {
+				continue
 			}
-		case err = <-w.Errors:
+			if timer == nil {
+				timer = time.NewTimer(w.debounce)
+				timerCh = timer.C
+			} else {
+				if !timer.Stop() {
+					<-timerCh
+				}
+				timer.Reset(w.debounce)
+			}
+		case <-timerCh:
+			timer = nil
+			timerCh = nil
+			onChange()
+		case err := <-w.fsw.Errors:
 			return err
 		}
 	}
 }
+
+// globToRegexp compiles a glob pattern where "**" matches any number of path
+// segments, "*" matches within a single segment and "?" matches one rune.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	p := filepath.ToSlash(pattern)
+	for i := 0; i < len(p); {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case p[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		case strings.ContainsRune(`.()+|^$[]{}\`, rune(p[i])):
+			b.WriteByte('\\')
+			b.WriteByte(p[i])
+			i++
+		default:
+			b.WriteByte(p[i])
+			i++
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// staticGlobPrefix returns the longest directory prefix of pattern that
+// contains no wildcard, e.g. "assets" for "assets/**/*.blend".
+func staticGlobPrefix(pattern string) string {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+
+	var static []string
+	for _, s := range segments {
+		if strings.ContainsAny(s, "*?") {
+			break
+		}
+		static = append(static, s)
+	}
+	return filepath.Join(static...)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Rapid-Vision/rv/cmd/internal/logs"
+	"github.com/Rapid-Vision/rv/cmd/internal/pack"
+	"github.com/spf13/cobra"
+)
+
+var packCmd = &cobra.Command{
+	Use:   "pack <dir>",
+	Short: "Archive an existing render output directory",
+	Long:  `Package dir into a single zip/tar/tar.gz archive next to it.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := args[0]
+		kind, _ := cmd.Flags().GetString("pack")
+
+		archivePath, err := pack.Archive(kind, dir)
+		if err != nil {
+			logs.Err.Fatalln(err)
+		}
+		if archivePath == "" {
+			fmt.Println("Nothing to do (--pack=none).")
+			return
+		}
+		fmt.Println("Wrote", archivePath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+
+	packCmd.Flags().String("pack", "zip", "Archive format: none, zip, tar or tar.gz")
+}
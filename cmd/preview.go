@@ -18,11 +18,16 @@ var previewCmd = &cobra.Command{
 		if err != nil {
 			log.Fatalf("Failed to get absolute path: %v", err)
 		}
+		runtime := resolveRuntime(cmd)
+		extraWatch, _ := cmd.Flags().GetStringArray("watch")
 
-		preview.Preview(scriptPath)
+		preview.Preview(scriptPath, runtime, extraWatch)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().StringArray("watch", nil, `Extra glob to watch, e.g. --watch "assets/**/*.blend" (repeatable)`)
+	addRuntimeFlags(previewCmd)
 }
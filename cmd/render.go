@@ -2,36 +2,105 @@ package cmd
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/Rapid-Vision/rv/cmd/internal/logs"
+	"github.com/Rapid-Vision/rv/cmd/internal/project"
 	"github.com/Rapid-Vision/rv/cmd/internal/render"
 	"github.com/spf13/cobra"
 )
 
 var renderCmd = &cobra.Command{
-	Use:   "render <script.py>",
+	Use:   "render [script.py]",
 	Short: "Render final dataset",
 	Long:  `Run generation script in several instances of blender and save resulting dataset.`,
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		scriptPath := args[0]
+		proj, projErr := project.Open(".")
+
+		sceneName, _ := cmd.Flags().GetString("scene")
+		var scene project.Scene
+		haveScene := false
+		if sceneName != "" {
+			if projErr != nil {
+				logs.Err.Fatalln(projErr)
+			}
+			var ok bool
+			scene, ok = proj.Scene(sceneName)
+			if !ok {
+				logs.Err.Fatalln("No such scene in rv.yaml:", sceneName)
+			}
+			haveScene = true
+		}
+
+		var scriptPath string
+		switch {
+		case len(args) == 1:
+			scriptPath = args[0]
+		case haveScene:
+			scriptPath = resolveProjectPath(proj, scene.Script)
+		default:
+			logs.Err.Fatalln("Provide <script.py> or --scene <name>")
+		}
+
 		imageNum, _ := cmd.Flags().GetInt("number")
+		if !cmd.Flags().Changed("number") && haveScene && scene.Number != 0 {
+			imageNum = scene.Number
+		}
+
 		procs, _ := cmd.Flags().GetInt("procs")
+		if !cmd.Flags().Changed("procs") && projErr == nil && proj.Manifest.Procs != 0 {
+			procs = proj.Manifest.Procs
+		}
+
+		seed, _ := cmd.Flags().GetInt("seed")
+		runtime := resolveRuntime(cmd)
+		keepLocal, _ := cmd.Flags().GetBool("keep-local")
+		storageParallelism, _ := cmd.Flags().GetInt("storage-parallelism")
+		packKind, _ := cmd.Flags().GetString("pack")
 
-		outputDir, _ := cmd.Flags().GetString("output")
-		outputDirAbs, err := filepath.Abs(outputDir)
-		if err != nil {
-			logs.Err.Fatalln("Failed to parse output path:", err)
+		outputURI, _ := cmd.Flags().GetString("output")
+		if !cmd.Flags().Changed("output") {
+			switch {
+			case haveScene && scene.Output != "":
+				outputURI = resolveProjectPath(proj, scene.Output)
+			case projErr == nil && proj.Manifest.DefaultOutput != "":
+				outputURI = resolveProjectPath(proj, proj.Manifest.DefaultOutput)
+			}
+		}
+		if !strings.Contains(outputURI, "://") {
+			abs, err := filepath.Abs(outputURI)
+			if err != nil {
+				logs.Err.Fatalln("Failed to parse output path:", err)
+			}
+			outputURI = abs
 		}
 
-		render.Render(scriptPath, imageNum, procs, outputDirAbs)
+		render.Render(scriptPath, imageNum, procs, outputURI, seed, runtime, keepLocal, storageParallelism, packKind)
 	},
 }
 
+// resolveProjectPath resolves a scenes: path (script or output) from rv.yaml
+// against the project root, so `rv render --scene <name>` works from any
+// subdirectory of the project, not just its root. URIs with a storage scheme
+// and already-absolute paths pass through unchanged.
+func resolveProjectPath(proj *project.Project, p string) string {
+	if p == "" || strings.Contains(p, "://") || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(proj.Dir, p)
+}
+
 func init() {
 	rootCmd.AddCommand(renderCmd)
 
 	renderCmd.Flags().IntP("number", "n", 1, "Number of total images generated")
 	renderCmd.Flags().IntP("procs", "p", 1, "Maximum number of spawned Blender processes")
-	renderCmd.Flags().StringP("output", "o", "./out", "Output directory")
+	renderCmd.Flags().StringP("output", "o", "./out", "Output directory, or a file://, s3:// or gs:// URI")
+	renderCmd.Flags().Int("seed", 0, "Base random seed; each worker gets seed+workerIndex")
+	renderCmd.Flags().Bool("keep-local", false, "Keep the local temp directory after uploading to a remote --output")
+	renderCmd.Flags().Int("storage-parallelism", 4, "Maximum concurrent uploads when --output is remote")
+	renderCmd.Flags().String("scene", "", "Render a scene by name from the project's rv.yaml instead of a script path")
+	renderCmd.Flags().String("pack", "none", "Archive the output directory after rendering: none, zip, tar or tar.gz")
+	addRuntimeFlags(renderCmd)
 }
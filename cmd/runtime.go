@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Rapid-Vision/rv/cmd/internal/container"
+	"github.com/Rapid-Vision/rv/cmd/internal/project"
+	"github.com/spf13/cobra"
+)
+
+// addRuntimeFlags registers the --runtime and --blender-version flags shared
+// by render and preview.
+func addRuntimeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("runtime", "local", "Where Blender executes: local, docker or podman")
+	cmd.Flags().String("blender-version", "latest", "Blender version to use when --runtime is a container runtime")
+}
+
+// resolveRuntime reads --runtime/--blender-version off cmd. When --runtime
+// wasn't explicitly set, it falls back to RV_RUNTIME, then to the enclosing
+// project's rv.yaml `runtime`, in that order.
+func resolveRuntime(cmd *cobra.Command) *container.RuntimeConfig {
+	runtime, _ := cmd.Flags().GetString("runtime")
+	if !cmd.Flags().Changed("runtime") {
+		if r := os.Getenv("RV_RUNTIME"); r != "" {
+			runtime = r
+		} else if proj, err := project.Open("."); err == nil && proj.Manifest.Runtime != "" {
+			runtime = proj.Manifest.Runtime
+		}
+	}
+
+	blenderVersion, _ := cmd.Flags().GetString("blender-version")
+
+	return &container.RuntimeConfig{Runtime: runtime, BlenderVersion: blenderVersion}
+}